@@ -3,24 +3,11 @@ package cloud_recording_service
 import (
 	"encoding/json"
 	"fmt"
-	"math/rand"
-	"strconv"
 	"strings"
 	"time"
 	"unicode"
 )
 
-// generateUID generates a unique user identifier for use within cloud recording sessions.
-// This function ensures the UID is never zero, which is reserved, by generating a random
-// number between 1 and the maximum possible 32-bit integer value.
-func (s *CloudRecordingService) GenerateUID() string {
-	// Generate a random number starting from 1 to avoid 0, which is reserved.
-	uid := rand.Intn(4294967294) + 1
-
-	// Convert the integer UID to a string format and return it.
-	return strconv.Itoa(uid)
-}
-
 // ValidateRecordingMode checks if a specific string is present within a slice of strings.
 // This is useful for determining if a particular item exists within a list.
 func (s *CloudRecordingService) ValidateRecordingMode(modeToCheck string) bool {
@@ -34,10 +21,10 @@ func (s *CloudRecordingService) ValidateRecordingMode(modeToCheck string) bool {
 }
 
 // AddTimestamp adds a current timestamp to any response object that supports the Timestampable interface.
+// The emitted format is controlled by s.TimestampFormat (RFC3339 by default).
 // It then marshals the updated object back into JSON format for further use or storage.
 func (s *CloudRecordingService) AddTimestamp(response Timestampable) (json.RawMessage, error) {
-	// Set the current timestamp in UTC and RFC3339 format.
-	now := time.Now().UTC().Format(time.RFC3339)
+	now := s.TimestampFormat.format(time.Now())
 	response.SetTimestamp(now)
 
 	// Marshal the response with the added timestamp back to JSON.
@@ -48,19 +35,75 @@ func (s *CloudRecordingService) AddTimestamp(response Timestampable) (json.RawMe
 	return timestampedBody, nil
 }
 
+// FileListPayload is the decoded form of ServerResponse.FileList. Exactly one
+// of StringEntries or JSONEntries is populated, depending on Mode, so that
+// downstream response DTOs can embed FileListPayload instead of handling the
+// raw fileListMode/fileList pair themselves.
+type FileListPayload struct {
+	Mode          string
+	StringEntries []FileDetail
+	JSONEntries   []FileListEntry
+}
+
+// UnmarshalJSON decodes a ServerResponse and, when fileListMode/fileList are
+// present, eagerly decodes FileList into FileListPayload so callers don't have
+// to remember to call UnmarshalFileList themselves.
+func (sr *ServerResponse) UnmarshalJSON(data []byte) error {
+	type alias ServerResponse
+	aux := &struct{ *alias }{alias: (*alias)(sr)}
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	if sr.FileListMode == nil || sr.FileList == nil {
+		return nil
+	}
+
+	payload, err := decodeFileListPayload(*sr.FileListMode, *sr.FileList)
+	if err != nil {
+		return err
+	}
+	sr.FileListPayload = payload
+	return nil
+}
+
 // UnmarshalFileList interprets the file list from the server response, handling different formats based on the FileListMode.
 // It supports 'string' and 'json' modes, returning the file list as either a slice of FileDetail or FileListEntry respectively.
+//
+// Deprecated: ServerResponse now decodes FileList into FileListPayload as part
+// of its own UnmarshalJSON. UnmarshalFileList is kept as a backwards-compatible
+// shim for existing callers.
 func (sr *ServerResponse) UnmarshalFileList() (interface{}, error) {
 	if sr.FileListMode == nil || sr.FileList == nil {
 		// Ensure FileListMode and FileList are not nil before proceeding.
 		return nil, fmt.Errorf("FileListMode or FileList are empty, cannot proceed with unmarshaling")
 	}
-	switch *sr.FileListMode {
+
+	payload, err := decodeFileListPayload(*sr.FileListMode, *sr.FileList)
+	if err != nil {
+		return nil, err
+	}
+
+	switch payload.Mode {
+	case "string":
+		return payload.StringEntries, nil
+	case "json":
+		return payload.JSONEntries, nil
+	default:
+		return nil, fmt.Errorf("unknown FileListMode: %s", payload.Mode)
+	}
+}
+
+// decodeFileListPayload parses raw into a FileListPayload according to mode,
+// folding in the recovery behavior for malformed "string" mode payloads that
+// Agora occasionally sends (trailing diagnostic text, or a bare "false").
+func decodeFileListPayload(mode string, raw json.RawMessage) (*FileListPayload, error) {
+	switch mode {
 	case "string":
 		// fileList is returned as a JSON-encoded string containing an array of file details.
 		// First unmarshal to a plain string and then decode the underlying JSON payload.
 		var rawString string
-		if err := json.Unmarshal(*sr.FileList, &rawString); err != nil {
+		if err := json.Unmarshal(raw, &rawString); err != nil {
 			return nil, fmt.Errorf("error parsing FileList into string: %v", err)
 		}
 		trimmed := strings.TrimSpace(rawString)
@@ -70,29 +113,29 @@ func (sr *ServerResponse) UnmarshalFileList() (interface{}, error) {
 			// recover by extracting the JSON array from the payload.
 			if candidate, ok := extractJSONArray(trimmed); ok {
 				if err2 := json.Unmarshal([]byte(candidate), &fileList); err2 == nil {
-					return fileList, nil
+					return &FileListPayload{Mode: mode, StringEntries: fileList}, nil
 				}
 			}
 
 			// If no JSON array could be located, interpret certain literals (e.g. "false")
 			// as an empty file list to gracefully handle Agora's non-array responses.
 			if looksLikeFalseLiteral(trimmed) {
-				return []FileDetail{}, nil
+				return &FileListPayload{Mode: mode, StringEntries: []FileDetail{}}, nil
 			}
 
 			return nil, fmt.Errorf("error parsing FileList into []FileDetail: %v", err)
 		}
-		return fileList, nil
+		return &FileListPayload{Mode: mode, StringEntries: fileList}, nil
 	case "json":
 		// Parse the file list as a slice of FileListEntry structures.
 		var fileList []FileListEntry
-		if err := json.Unmarshal(*sr.FileList, &fileList); err != nil {
+		if err := json.Unmarshal(raw, &fileList); err != nil {
 			return nil, fmt.Errorf("error parsing FileList into []FileListEntry: %v", err)
 		}
-		return fileList, nil
+		return &FileListPayload{Mode: mode, JSONEntries: fileList}, nil
 	default:
 		// Handle unknown FileListMode by returning an error.
-		return nil, fmt.Errorf("unknown FileListMode: %s", *sr.FileListMode)
+		return nil, fmt.Errorf("unknown FileListMode: %s", mode)
 	}
 }
 
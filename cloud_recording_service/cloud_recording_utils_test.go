@@ -0,0 +1,90 @@
+package cloud_recording_service
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDecodeFileListPayloadStringMode(t *testing.T) {
+	raw := json.RawMessage(`"[{\"fileName\":\"a.mp4\",\"trackType\":\"audio_and_video\",\"uid\":\"123\",\"mixedAllUser\":true,\"isPlayable\":true,\"sliceStartTime\":1690000000}]"`)
+
+	payload, err := decodeFileListPayload("string", raw)
+	if err != nil {
+		t.Fatalf("decodeFileListPayload returned error: %v", err)
+	}
+	if payload.Mode != "string" {
+		t.Fatalf("expected mode %q, got %q", "string", payload.Mode)
+	}
+	if len(payload.StringEntries) != 1 || payload.StringEntries[0].FileName != "a.mp4" {
+		t.Fatalf("unexpected StringEntries: %+v", payload.StringEntries)
+	}
+}
+
+func TestDecodeFileListPayloadStringModeRecoversTrailingDiagnosticText(t *testing.T) {
+	// Agora occasionally appends non-JSON diagnostic text after the array.
+	raw := json.RawMessage(`"[{\"fileName\":\"a.mp4\"}] some diagnostic suffix"`)
+
+	payload, err := decodeFileListPayload("string", raw)
+	if err != nil {
+		t.Fatalf("decodeFileListPayload returned error: %v", err)
+	}
+	if len(payload.StringEntries) != 1 || payload.StringEntries[0].FileName != "a.mp4" {
+		t.Fatalf("unexpected StringEntries after recovery: %+v", payload.StringEntries)
+	}
+}
+
+func TestDecodeFileListPayloadStringModeFalseLiteralIsEmptyList(t *testing.T) {
+	raw := json.RawMessage(`"false"`)
+
+	payload, err := decodeFileListPayload("string", raw)
+	if err != nil {
+		t.Fatalf("decodeFileListPayload returned error: %v", err)
+	}
+	if payload.StringEntries == nil || len(payload.StringEntries) != 0 {
+		t.Fatalf("expected an empty, non-nil StringEntries slice, got %+v", payload.StringEntries)
+	}
+}
+
+func TestDecodeFileListPayloadJSONMode(t *testing.T) {
+	raw := json.RawMessage(`[{"fileName":"b.mp4","trackType":"audio_and_video","uid":"456"}]`)
+
+	payload, err := decodeFileListPayload("json", raw)
+	if err != nil {
+		t.Fatalf("decodeFileListPayload returned error: %v", err)
+	}
+	if len(payload.JSONEntries) != 1 || payload.JSONEntries[0].FileName != "b.mp4" {
+		t.Fatalf("unexpected JSONEntries: %+v", payload.JSONEntries)
+	}
+}
+
+func TestDecodeFileListPayloadUnknownMode(t *testing.T) {
+	if _, err := decodeFileListPayload("xml", json.RawMessage(`null`)); err == nil {
+		t.Fatal("expected an error for an unknown FileListMode, got nil")
+	}
+}
+
+func TestServerResponseUnmarshalJSONPopulatesFileListPayload(t *testing.T) {
+	body := []byte(`{"status":0,"fileListMode":"json","fileList":[{"fileName":"c.mp4"}]}`)
+
+	var sr ServerResponse
+	if err := json.Unmarshal(body, &sr); err != nil {
+		t.Fatalf("UnmarshalJSON returned error: %v", err)
+	}
+
+	if sr.FileListPayload == nil {
+		t.Fatal("expected FileListPayload to be populated by UnmarshalJSON")
+	}
+	if len(sr.FileListPayload.JSONEntries) != 1 || sr.FileListPayload.JSONEntries[0].FileName != "c.mp4" {
+		t.Fatalf("unexpected JSONEntries: %+v", sr.FileListPayload.JSONEntries)
+	}
+
+	// UnmarshalFileList should agree with what UnmarshalJSON already computed.
+	fileList, err := sr.UnmarshalFileList()
+	if err != nil {
+		t.Fatalf("UnmarshalFileList returned error: %v", err)
+	}
+	entries, ok := fileList.([]FileListEntry)
+	if !ok || len(entries) != 1 || entries[0].FileName != "c.mp4" {
+		t.Fatalf("unexpected UnmarshalFileList result: %+v", fileList)
+	}
+}
@@ -0,0 +1,150 @@
+package cloud_recording_service
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// idempotencyTTL is how long a cached response stays eligible to short-circuit
+// a retried operation before DoIdempotent will issue the call again.
+const idempotencyTTL = 10 * time.Minute
+
+// IdempotencyStore caches responses to outbound Cloud Recording API calls,
+// keyed by operation key, so a retried call can return the original response
+// instead of re-issuing a request Agora may have already accepted. The
+// default is InMemoryIdempotencyStore; deployments running multiple replicas
+// should supply a shared store instead, e.g. a Redis-backed implementation of
+// this interface.
+type IdempotencyStore interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, response []byte, ttl time.Duration)
+}
+
+// InMemoryIdempotencyStore is the default IdempotencyStore: an in-process map
+// with per-entry TTL. It is not shared across processes or replicas.
+type InMemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	entries map[string]idempotencyEntry
+}
+
+type idempotencyEntry struct {
+	response  []byte
+	expiresAt time.Time
+}
+
+// NewInMemoryIdempotencyStore creates an empty InMemoryIdempotencyStore.
+func NewInMemoryIdempotencyStore() *InMemoryIdempotencyStore {
+	return &InMemoryIdempotencyStore{entries: make(map[string]idempotencyEntry)}
+}
+
+// Get implements IdempotencyStore.
+func (s *InMemoryIdempotencyStore) Get(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(s.entries, key)
+		return nil, false
+	}
+	return entry.response, true
+}
+
+// Set implements IdempotencyStore.
+func (s *InMemoryIdempotencyStore) Set(key string, response []byte, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = idempotencyEntry{response: response, expiresAt: time.Now().Add(ttl)}
+}
+
+// NewIdempotencyKey returns a new UUIDv4 to use as a stable client-side
+// idempotency key for a single logical acquire/start/stop operation.
+func NewIdempotencyKey() (string, error) {
+	var buf [16]byte
+	if _, err := rand.Read(buf[:]); err != nil {
+		return "", fmt.Errorf("error generating idempotency key: %v", err)
+	}
+	buf[6] = (buf[6] & 0x0f) | 0x40 // version 4
+	buf[8] = (buf[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", buf[0:4], buf[4:6], buf[6:8], buf[8:10], buf[10:16]), nil
+}
+
+// IdempotentCall is an outbound Cloud Recording API call (acquire/start/stop)
+// made with the given idempotency key attached (e.g. as a request header),
+// returning the raw response body alongside the HTTP status code and any
+// Agora-reported error code so DoIdempotent's RetryPolicy can decide whether
+// to retry.
+type IdempotentCall func(ctx context.Context, idempotencyKey string) (response []byte, statusCode int, agoraErrorCode int, err error)
+
+// DoIdempotent executes call under s.RetryPolicy, attaching a single
+// idempotency key to every attempt so Agora can recognize retries of the
+// same logical operation. If s.IdempotencyStore already has a cached
+// response for operationKey - because a previous, separate invocation of
+// DoIdempotent for the same logical operation already succeeded - that
+// response is returned without calling call again.
+func (s *CloudRecordingService) DoIdempotent(ctx context.Context, operationKey string, call IdempotentCall) ([]byte, error) {
+	store := s.idempotencyStore()
+
+	if cached, ok := store.Get(operationKey); ok {
+		return cached, nil
+	}
+
+	idempotencyKey, err := NewIdempotencyKey()
+	if err != nil {
+		return nil, err
+	}
+
+	policy := s.RetryPolicy
+	if policy == (RetryPolicy{}) {
+		policy = DefaultRetryPolicy
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < policy.MaxAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(policy.backoff(attempt - 1)):
+			}
+		}
+
+		response, statusCode, agoraErrorCode, callErr := call(ctx, idempotencyKey)
+		if callErr == nil && statusCode < 300 && agoraErrorCode == 0 {
+			store.Set(operationKey, response, idempotencyTTL)
+			return response, nil
+		}
+
+		lastErr = callErr
+		if !policy.shouldRetry(statusCode, agoraErrorCode, callErr) {
+			if callErr != nil {
+				return nil, callErr
+			}
+			return nil, fmt.Errorf("cloud recording request failed with status %d (Agora error code %d)", statusCode, agoraErrorCode)
+		}
+	}
+
+	return nil, fmt.Errorf("cloud recording request did not succeed after %d attempts: %v", policy.MaxAttempts, lastErr)
+}
+
+// idempotencyStore returns s.IdempotencyStore, lazily creating and
+// persisting an InMemoryIdempotencyStore onto s if it's unset. Persisting
+// the default back onto s (rather than returning a throwaway store) is what
+// makes cross-invocation idempotency work on a zero-value
+// *CloudRecordingService: every call has to see the same store, not a fresh
+// one that forgets its cache as soon as the call returns.
+func (s *CloudRecordingService) idempotencyStore() IdempotencyStore {
+	s.storeMu.Lock()
+	defer s.storeMu.Unlock()
+
+	if s.IdempotencyStore == nil {
+		s.IdempotencyStore = NewInMemoryIdempotencyStore()
+	}
+	return s.IdempotencyStore
+}
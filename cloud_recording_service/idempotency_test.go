@@ -0,0 +1,119 @@
+package cloud_recording_service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDoIdempotentCachesAcrossInvocationsOnZeroValueService(t *testing.T) {
+	s := &CloudRecordingService{}
+
+	calls := 0
+	call := func(ctx context.Context, idempotencyKey string) ([]byte, int, int, error) {
+		calls++
+		return []byte("ok"), 200, 0, nil
+	}
+
+	if _, err := s.DoIdempotent(context.Background(), "acquire:channel-a", call); err != nil {
+		t.Fatalf("first DoIdempotent returned error: %v", err)
+	}
+	if _, err := s.DoIdempotent(context.Background(), "acquire:channel-a", call); err != nil {
+		t.Fatalf("second DoIdempotent returned error: %v", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected the underlying call to run once across both invocations, got %d", calls)
+	}
+}
+
+func TestDoIdempotentUsesSameIdempotencyKeyAcrossRetries(t *testing.T) {
+	s := &CloudRecordingService{
+		RetryPolicy: RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+	}
+
+	var seenKeys []string
+	attempt := 0
+	call := func(ctx context.Context, idempotencyKey string) ([]byte, int, int, error) {
+		seenKeys = append(seenKeys, idempotencyKey)
+		attempt++
+		if attempt < 3 {
+			return nil, 503, 0, nil
+		}
+		return []byte("ok"), 200, 0, nil
+	}
+
+	if _, err := s.DoIdempotent(context.Background(), "start:channel-b", call); err != nil {
+		t.Fatalf("DoIdempotent returned error: %v", err)
+	}
+
+	if len(seenKeys) != 3 {
+		t.Fatalf("expected 3 attempts, got %d", len(seenKeys))
+	}
+	for _, k := range seenKeys[1:] {
+		if k != seenKeys[0] {
+			t.Fatalf("expected every retry to reuse idempotency key %q, got %q", seenKeys[0], k)
+		}
+	}
+}
+
+func TestDoIdempotentStopsRetryingOnNonTransientError(t *testing.T) {
+	s := &CloudRecordingService{
+		RetryPolicy: RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+	}
+
+	calls := 0
+	call := func(ctx context.Context, idempotencyKey string) ([]byte, int, int, error) {
+		calls++
+		return nil, 400, 0, nil
+	}
+
+	if _, err := s.DoIdempotent(context.Background(), "start:channel-c", call); err == nil {
+		t.Fatal("expected an error for a non-retryable 400 response")
+	}
+	if calls != 1 {
+		t.Fatalf("expected exactly one attempt for a non-retryable status, got %d", calls)
+	}
+}
+
+func TestRetryPolicyShouldRetry(t *testing.T) {
+	p := DefaultRetryPolicy
+
+	if !p.shouldRetry(500, 0, nil) {
+		t.Error("expected a 5xx status to be retryable")
+	}
+	if p.shouldRetry(400, 0, nil) {
+		t.Error("expected a 4xx status to not be retryable")
+	}
+	if !p.shouldRetry(200, 65, nil) {
+		t.Error("expected a known transient Agora error code to be retryable")
+	}
+	if p.shouldRetry(200, 999, nil) {
+		t.Error("expected an unknown Agora error code to not be retryable")
+	}
+	if !p.shouldRetry(0, 0, errNetworkTimeout{}) {
+		t.Error("expected a net.Error to be retryable")
+	}
+	if p.shouldRetry(0, 0, errors.New("some other error")) {
+		t.Error("expected a non-net.Error to not be retryable")
+	}
+}
+
+func TestRetryPolicyBackoffRespectsMaxDelay(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Second, MaxDelay: 2 * time.Second}
+
+	for attempt := 0; attempt < 10; attempt++ {
+		if d := p.backoff(attempt); d > p.MaxDelay {
+			t.Fatalf("backoff(%d) = %v, exceeds MaxDelay %v", attempt, d, p.MaxDelay)
+		}
+	}
+}
+
+// errNetworkTimeout is a minimal net.Error for exercising shouldRetry's
+// network-error path without depending on a real network failure.
+type errNetworkTimeout struct{}
+
+func (errNetworkTimeout) Error() string   { return "network timeout" }
+func (errNetworkTimeout) Timeout() bool   { return true }
+func (errNetworkTimeout) Temporary() bool { return true }
@@ -0,0 +1,58 @@
+package cloud_recording_service
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"net"
+	"time"
+)
+
+// RetryPolicy controls how DoIdempotent retries an outbound Cloud Recording
+// API call. Retries use exponential backoff with full jitter and are only
+// attempted for 5xx responses, network errors, and Agora's own transient
+// error codes, since acquire/start/stop calls are not safe to blindly retry
+// otherwise.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy is used by NewCloudRecordingService when no policy is supplied.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+}
+
+// transientAgoraErrorCodes are Agora Cloud Recording response codes known to
+// mean the request was not applied and is therefore safe to retry.
+var transientAgoraErrorCodes = map[int]bool{
+	65: true, // dynamic key expired before the request was processed
+	67: true, // resource not found, most often a transient allocation race
+}
+
+// backoff returns the delay to wait before retry attempt n (0-indexed),
+// growing exponentially from BaseDelay and capped at MaxDelay, with full
+// jitter to avoid every caller retrying in lockstep.
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	delay := float64(p.BaseDelay) * math.Pow(2, float64(attempt))
+	if max := float64(p.MaxDelay); delay > max {
+		delay = max
+	}
+	return time.Duration(rand.Float64() * delay)
+}
+
+// shouldRetry reports whether a response with the given HTTP status code and
+// Agora error code (or the given transport error) is safe to retry.
+func (p RetryPolicy) shouldRetry(statusCode, agoraErrorCode int, err error) bool {
+	if err != nil {
+		var netErr net.Error
+		return errors.As(err, &netErr)
+	}
+	if statusCode >= 500 {
+		return true
+	}
+	return transientAgoraErrorCodes[agoraErrorCode]
+}
@@ -0,0 +1,66 @@
+package cloud_recording_service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimestampFormat selects the wire format CloudRecordingService uses when it
+// stamps a response via AddTimestamp. Agora's own callbacks, and some
+// downstream consumers, deliver timestamps as Unix seconds rather than
+// RFC3339, so operators can pick whichever format their consumers expect.
+type TimestampFormat int
+
+const (
+	// TimestampFormatRFC3339 formats timestamps as RFC3339 in UTC, e.g.
+	// "2026-07-26T00:00:00Z". This is the default.
+	TimestampFormatRFC3339 TimestampFormat = iota
+	// TimestampFormatUnixSeconds formats timestamps as a Unix seconds count, e.g. "1753488000".
+	TimestampFormatUnixSeconds
+	// TimestampFormatUnixMillis formats timestamps as a Unix milliseconds count, e.g. "1753488000000".
+	TimestampFormatUnixMillis
+)
+
+// format renders now according to f.
+func (f TimestampFormat) format(now time.Time) string {
+	switch f {
+	case TimestampFormatUnixSeconds:
+		return strconv.FormatInt(now.Unix(), 10)
+	case TimestampFormatUnixMillis:
+		return strconv.FormatInt(now.UnixMilli(), 10)
+	default:
+		return now.UTC().Format(time.RFC3339)
+	}
+}
+
+// ParseTimestamp parses a timestamp as either RFC3339 or Unix-epoch seconds
+// (with optional fractional nanoseconds, e.g. "1753488000.123456789"). It
+// tries RFC3339 first and falls back to the Unix-epoch form, so it can read
+// back timestamps written in either TimestampFormat.
+func ParseTimestamp(data []byte) (time.Time, error) {
+	var t time.Time
+	if err := t.UnmarshalText(data); err == nil {
+		return t, nil
+	}
+
+	secStr, nsecStr, _ := strings.Cut(string(data), ".")
+
+	sec, err := strconv.ParseInt(secStr, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error parsing timestamp %q: not RFC3339 or Unix-epoch", string(data))
+	}
+
+	var nsec int64
+	if nsecStr != "" {
+		// Right-pad to 9 digits so "123" is parsed as 123ms rather than 123ns.
+		nsecStr = (nsecStr + "000000000")[:9]
+		nsec, err = strconv.ParseInt(nsecStr, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("error parsing fractional seconds of timestamp %q: %v", string(data), err)
+		}
+	}
+
+	return time.Unix(sec, nsec).UTC(), nil
+}
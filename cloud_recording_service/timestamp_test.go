@@ -0,0 +1,67 @@
+package cloud_recording_service
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimestampRFC3339(t *testing.T) {
+	got, err := ParseTimestamp([]byte("2026-07-26T00:00:00Z"))
+	if err != nil {
+		t.Fatalf("ParseTimestamp returned error: %v", err)
+	}
+
+	want := time.Date(2026, 7, 26, 0, 0, 0, 0, time.UTC)
+	if !got.Equal(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestParseTimestampUnixSeconds(t *testing.T) {
+	got, err := ParseTimestamp([]byte("1753488000"))
+	if err != nil {
+		t.Fatalf("ParseTimestamp returned error: %v", err)
+	}
+
+	want := time.Unix(1753488000, 0).UTC()
+	if !got.Equal(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestParseTimestampUnixSecondsWithFractionalNanoseconds(t *testing.T) {
+	got, err := ParseTimestamp([]byte("1753488000.123456789"))
+	if err != nil {
+		t.Fatalf("ParseTimestamp returned error: %v", err)
+	}
+
+	want := time.Unix(1753488000, 123456789).UTC()
+	if !got.Equal(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
+
+func TestParseTimestampInvalid(t *testing.T) {
+	if _, err := ParseTimestamp([]byte("not-a-timestamp")); err == nil {
+		t.Fatal("expected an error for an unparseable timestamp, got nil")
+	}
+}
+
+func TestTimestampFormatFormat(t *testing.T) {
+	now := time.Date(2026, 7, 26, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		format TimestampFormat
+		want   string
+	}{
+		{TimestampFormatRFC3339, "2026-07-26T00:00:00Z"},
+		{TimestampFormatUnixSeconds, "1785024000"},
+		{TimestampFormatUnixMillis, "1785024000000"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.format.format(now); got != tt.want {
+			t.Errorf("TimestampFormat(%d).format(%v) = %q, want %q", tt.format, now, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package cloud_recording_service
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// CloudRecordingService wraps the Agora Cloud Recording REST API, providing
+// higher-level helpers (UID allocation, timestamping, response decoding) on
+// top of the raw acquire/start/stop/query calls.
+type CloudRecordingService struct {
+	// TimestampFormat controls the format AddTimestamp emits. It defaults to
+	// TimestampFormatRFC3339 when left unset.
+	TimestampFormat TimestampFormat
+
+	// RetryPolicy controls retry behavior for outbound Cloud Recording API
+	// calls made through DoIdempotent. It defaults to DefaultRetryPolicy
+	// when left unset.
+	RetryPolicy RetryPolicy
+
+	// IdempotencyStore caches responses to outbound calls made through
+	// DoIdempotent, keyed by operation key. It defaults to a fresh
+	// InMemoryIdempotencyStore when left unset; use idempotencyStore() to
+	// read it so that default gets persisted rather than recreated per call.
+	IdempotencyStore IdempotencyStore
+
+	storeMu sync.Mutex
+
+	// channelUIDs tracks, per channel, the set of UIDs GenerateUID has
+	// allocated for this service instance. Each value is a *sync.Map used as
+	// a set (uid -> struct{}). The zero value is ready to use.
+	channelUIDs sync.Map
+
+	// uidChannels is the reverse index from an allocated UID back to the
+	// channel it was allocated for, so ReleaseUID can free it without the
+	// caller having to remember which channel it came from.
+	uidChannels sync.Map
+}
+
+// NewCloudRecordingService constructs a CloudRecordingService. A zero-value
+// retryPolicy is replaced with DefaultRetryPolicy, and a nil store is
+// replaced with a new InMemoryIdempotencyStore.
+func NewCloudRecordingService(timestampFormat TimestampFormat, retryPolicy RetryPolicy, store IdempotencyStore) *CloudRecordingService {
+	if retryPolicy == (RetryPolicy{}) {
+		retryPolicy = DefaultRetryPolicy
+	}
+	if store == nil {
+		store = NewInMemoryIdempotencyStore()
+	}
+
+	return &CloudRecordingService{
+		TimestampFormat:  timestampFormat,
+		RetryPolicy:      retryPolicy,
+		IdempotencyStore: store,
+	}
+}
+
+// Timestampable is implemented by response types that carry a server-set
+// timestamp, allowing AddTimestamp to stamp them generically and, on the
+// read side, allowing ParseTimestamp-based consumers to read the value back.
+type Timestampable interface {
+	SetTimestamp(string)
+	GetTimestamp() string
+}
+
+// FileDetail describes a single recorded file when the server reports its
+// file list in "string" mode (a JSON-encoded string embedded in the payload).
+type FileDetail struct {
+	FileName       string `json:"fileName"`
+	TrackType      string `json:"trackType"`
+	Uid            string `json:"uid"`
+	MixedAllUser   bool   `json:"mixedAllUser"`
+	IsPlayable     bool   `json:"isPlayable"`
+	SliceStartTime int64  `json:"sliceStartTime"`
+}
+
+// FileListEntry describes a single recorded file when the server reports its
+// file list in "json" mode (a native JSON array rather than an encoded string).
+type FileListEntry struct {
+	FileName       string `json:"fileName"`
+	TrackType      string `json:"trackType"`
+	Uid            string `json:"uid"`
+	MixedAllUser   bool   `json:"mixedAllUser"`
+	IsPlayable     bool   `json:"isPlayable"`
+	SliceStartTime int64  `json:"sliceStartTime"`
+}
+
+// ServerResponse is the "serverResponse" object embedded in Agora Cloud
+// Recording query responses.
+type ServerResponse struct {
+	Status       int              `json:"status,omitempty"`
+	FileListMode *string          `json:"fileListMode,omitempty"`
+	FileList     *json.RawMessage `json:"fileList,omitempty"`
+
+	// FileListPayload holds the decoded form of FileList, populated by
+	// UnmarshalJSON. It is not part of the wire format.
+	FileListPayload *FileListPayload `json:"-"`
+}
@@ -0,0 +1,79 @@
+package cloud_recording_service
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"sync"
+)
+
+// maxUIDGenerationAttempts bounds how many UIDs GenerateUID will draw in total
+// for one call, redrawing whenever a draw is already in use for the channel,
+// before giving up.
+const maxUIDGenerationAttempts = 10
+
+// GenerateUID generates a cryptographically random, unique user identifier for
+// use within a cloud recording session on the given channel. It replaces the
+// previous math/rand-based generator, which reused the same sequence across
+// process restarts and could collide with UIDs already in use. Collisions
+// against UIDs currently allocated for the channel on this service instance
+// cause a redraw, with at most maxUIDGenerationAttempts draws in total before
+// an error is returned. Callers should call ReleaseUID once the recording
+// session the UID was allocated to ends.
+//
+// Allocation state is tracked per CloudRecordingService instance: two
+// services (e.g. serving two different Agora App IDs) keep separate state
+// even if they happen to use the same channel name, so neither one sees or
+// can release the UIDs allocated by the other.
+func (s *CloudRecordingService) GenerateUID(channel string) (string, error) {
+	store, _ := s.channelUIDs.LoadOrStore(channel, &sync.Map{})
+	channelStore := store.(*sync.Map)
+
+	for attempt := 0; attempt < maxUIDGenerationAttempts; attempt++ {
+		uid, err := randomUID()
+		if err != nil {
+			return "", fmt.Errorf("error generating random UID: %v", err)
+		}
+
+		if _, inUse := channelStore.LoadOrStore(uid, struct{}{}); !inUse {
+			s.uidChannels.Store(uid, channel)
+			return uid, nil
+		}
+	}
+
+	return "", fmt.Errorf("could not allocate a unique UID for channel %q after %d attempts", channel, maxUIDGenerationAttempts)
+}
+
+// ReleaseUID frees a UID previously returned by GenerateUID so it can be
+// allocated again, e.g. once the recording session it was used for has ended.
+// Releasing a UID that is not currently allocated on this service instance is
+// a no-op.
+func (s *CloudRecordingService) ReleaseUID(uid string) {
+	channel, ok := s.uidChannels.LoadAndDelete(uid)
+	if !ok {
+		return
+	}
+
+	if store, ok := s.channelUIDs.Load(channel); ok {
+		store.(*sync.Map).Delete(uid)
+	}
+}
+
+// randomUID reads 4 cryptographically random bytes and maps them into the
+// range [1, 2^32-1], rejecting and redrawing on the reserved value 0.
+func randomUID() (string, error) {
+	for {
+		var buf [4]byte
+		if _, err := rand.Read(buf[:]); err != nil {
+			return "", fmt.Errorf("error reading random bytes: %v", err)
+		}
+
+		value := binary.BigEndian.Uint32(buf[:])
+		if value == 0 {
+			continue
+		}
+
+		return strconv.FormatUint(uint64(value), 10), nil
+	}
+}
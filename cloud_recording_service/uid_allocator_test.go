@@ -0,0 +1,80 @@
+package cloud_recording_service
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestGenerateUIDNeverReturnsZeroAndReleaseAllowsReuse(t *testing.T) {
+	s := &CloudRecordingService{}
+
+	uid, err := s.GenerateUID("channel-a")
+	if err != nil {
+		t.Fatalf("GenerateUID returned error: %v", err)
+	}
+	if uid == "0" || uid == "" {
+		t.Fatalf("GenerateUID returned reserved/empty uid %q", uid)
+	}
+
+	s.ReleaseUID(uid)
+
+	if store, ok := s.channelUIDs.Load("channel-a"); ok {
+		if _, stillThere := store.(*sync.Map).Load(uid); stillThere {
+			t.Fatalf("ReleaseUID(%q) did not free the uid for reuse", uid)
+		}
+	}
+}
+
+func TestGenerateUIDTracksInUseUIDsPerChannel(t *testing.T) {
+	s := &CloudRecordingService{}
+	const channel = "channel-collision-test"
+
+	store, _ := s.channelUIDs.LoadOrStore(channel, &sync.Map{})
+	channelStore := store.(*sync.Map)
+
+	uid, err := s.GenerateUID(channel)
+	if err != nil {
+		t.Fatalf("GenerateUID returned error: %v", err)
+	}
+
+	// uid is now occupied; a second call must not return the same value.
+	second, err := s.GenerateUID(channel)
+	if err != nil {
+		t.Fatalf("GenerateUID returned error on second allocation: %v", err)
+	}
+	if second == uid {
+		t.Fatalf("GenerateUID returned a uid already in use for the channel: %q", uid)
+	}
+
+	if _, ok := channelStore.Load(uid); !ok {
+		t.Fatalf("expected %q to be tracked as in-use for %q", uid, channel)
+	}
+
+	s.ReleaseUID(uid)
+	s.ReleaseUID(second)
+}
+
+func TestGenerateUIDAllocationStateIsPerInstance(t *testing.T) {
+	s1 := &CloudRecordingService{}
+	s2 := &CloudRecordingService{}
+	const channel = "channel-shared-name"
+
+	uid, err := s1.GenerateUID(channel)
+	if err != nil {
+		t.Fatalf("s1.GenerateUID returned error: %v", err)
+	}
+
+	// s2 knows nothing about s1's allocation, so releasing a uid s1 never
+	// gave it must not touch s1's tracking for the same channel name.
+	s2.ReleaseUID(uid)
+
+	store, ok := s1.channelUIDs.Load(channel)
+	if !ok {
+		t.Fatalf("expected s1 to still track channel %q", channel)
+	}
+	if _, stillThere := store.(*sync.Map).Load(uid); !stillThere {
+		t.Fatalf("s2.ReleaseUID(%q) incorrectly freed a uid still held by s1", uid)
+	}
+
+	s1.ReleaseUID(uid)
+}
@@ -0,0 +1,66 @@
+package notifications
+
+import (
+	"container/list"
+	"sync"
+)
+
+// noticeCache is a small fixed-capacity LRU set of noticeIds. Agora retries a
+// callback with the same noticeId until it receives a 2xx, so handlers must
+// not be invoked twice for the same noticeId; the cache makes that check
+// cheap without growing unbounded over a long-running process.
+type noticeCache struct {
+	mu       sync.Mutex
+	capacity int
+	ll       *list.List
+	index    map[string]*list.Element
+}
+
+func newNoticeCache(capacity int) *noticeCache {
+	return &noticeCache{
+		capacity: capacity,
+		ll:       list.New(),
+		index:    make(map[string]*list.Element),
+	}
+}
+
+// markIfNew reports whether noticeID has not been seen before, atomically
+// marking it seen if so. The check and the mark happen under the same lock
+// acquisition: two concurrent requests for the same noticeID (Agora retries
+// can race with a slow first response) must not both observe "new".
+func (c *noticeCache) markIfNew(noticeID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.index[noticeID]; ok {
+		c.ll.MoveToFront(elem)
+		return false
+	}
+
+	elem := c.ll.PushFront(noticeID)
+	c.index[noticeID] = elem
+
+	for c.ll.Len() > c.capacity {
+		oldest := c.ll.Back()
+		if oldest == nil {
+			break
+		}
+		c.ll.Remove(oldest)
+		delete(c.index, oldest.Value.(string))
+	}
+
+	return true
+}
+
+// unmark undoes a markIfNew reservation for noticeID. Callers use this when
+// they marked a noticeID new but then failed to actually dispatch it (e.g.
+// the worker queue was full), so a retry isn't silently swallowed.
+func (c *noticeCache) unmark(noticeID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if elem, ok := c.index[noticeID]; ok {
+		c.ll.Remove(elem)
+		delete(c.index, noticeID)
+	}
+}
@@ -0,0 +1,62 @@
+package notifications
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestNoticeCacheMarkIfNewIsAtomicUnderConcurrency(t *testing.T) {
+	c := newNoticeCache(16)
+
+	const attempts = 50
+	var wg sync.WaitGroup
+	results := make([]bool, attempts)
+
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = c.markIfNew("same-notice-id")
+		}(i)
+	}
+	wg.Wait()
+
+	newCount := 0
+	for _, r := range results {
+		if r {
+			newCount++
+		}
+	}
+	if newCount != 1 {
+		t.Fatalf("expected exactly one concurrent caller to observe \"new\", got %d", newCount)
+	}
+}
+
+func TestNoticeCacheUnmarkAllowsRetry(t *testing.T) {
+	c := newNoticeCache(16)
+
+	if !c.markIfNew("id-1") {
+		t.Fatal("expected first markIfNew to report new")
+	}
+	if c.markIfNew("id-1") {
+		t.Fatal("expected second markIfNew to report already seen")
+	}
+
+	c.unmark("id-1")
+
+	if !c.markIfNew("id-1") {
+		t.Fatal("expected markIfNew after unmark to report new again")
+	}
+}
+
+func TestNoticeCacheEvictsOldestBeyondCapacity(t *testing.T) {
+	c := newNoticeCache(2)
+
+	c.markIfNew("a")
+	c.markIfNew("b")
+	c.markIfNew("c") // evicts "a"
+
+	if !c.markIfNew("a") {
+		t.Fatal("expected \"a\" to have been evicted and treated as new again")
+	}
+}
@@ -0,0 +1,139 @@
+package notifications
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/berkeugur/agora-go-backend-middleware/cloud_recording_service"
+)
+
+// Agora-assigned Cloud Recording notification event types. See Agora's Cloud
+// Recording Notification Callback documentation for the authoritative list;
+// only the event types this package decodes into typed structs are listed
+// here, everything else is surfaced as a RawEvent.
+const (
+	EventTypeRecorderExited = 1
+	EventTypeUploaded       = 30
+	EventTypeSliceStarted   = 40
+)
+
+// Event is implemented by every typed notification payload.
+type Event interface {
+	// EventType returns the Agora eventType this event was decoded from.
+	EventType() int
+}
+
+// BaseEvent carries the envelope fields common to every event.
+type BaseEvent struct {
+	NoticeID string `json:"-"`
+	Type     int    `json:"-"`
+	NotifyMs int64  `json:"-"`
+}
+
+// EventType implements Event.
+func (b BaseEvent) EventType() int { return b.Type }
+
+// baseSetter is implemented by every concrete event type so decodeEvent can
+// populate the embedded BaseEvent's envelope fields after decoding the payload.
+type baseSetter interface {
+	base() *BaseEvent
+}
+
+// RecordingExitEvent reports that a recording session has exited, whether
+// normally or due to an error.
+type RecordingExitEvent struct {
+	BaseEvent
+	Cname       string          `json:"cname"`
+	Uid         string          `json:"uid"`
+	Sid         string          `json:"sid"`
+	ServiceType int             `json:"serviceType"`
+	Details     json.RawMessage `json:"details"`
+}
+
+func (e *RecordingExitEvent) base() *BaseEvent { return &e.BaseEvent }
+
+// UploadedEvent reports that a recording's files have finished uploading to
+// the configured storage destination. Files is decoded via
+// cloud_recording_service.ServerResponse, reusing its fileListMode-aware
+// decoding so upload events and query responses stay in sync.
+type UploadedEvent struct {
+	BaseEvent
+	Cname string                                   `json:"cname"`
+	Sid   string                                   `json:"sid"`
+	Files *cloud_recording_service.FileListPayload `json:"-"`
+}
+
+func (e *UploadedEvent) base() *BaseEvent { return &e.BaseEvent }
+
+// UnmarshalJSON decodes the event's own fields, then decodes the embedded
+// file list by delegating to cloud_recording_service.ServerResponse so the
+// same fileListMode handling (including its recovery paths) applies here.
+func (e *UploadedEvent) UnmarshalJSON(data []byte) error {
+	type alias UploadedEvent
+	aux := &struct{ *alias }{alias: (*alias)(e)}
+	if err := json.Unmarshal(data, aux); err != nil {
+		return err
+	}
+
+	var sr cloud_recording_service.ServerResponse
+	if err := json.Unmarshal(data, &sr); err != nil {
+		return fmt.Errorf("error decoding file list from uploaded event payload: %v", err)
+	}
+	e.Files = sr.FileListPayload
+	return nil
+}
+
+// SliceStartEvent reports that a new recording slice (segment) has been created.
+type SliceStartEvent struct {
+	BaseEvent
+	Cname   string `json:"cname"`
+	Sid     string `json:"sid"`
+	SliceId string `json:"sliceId"`
+}
+
+func (e *SliceStartEvent) base() *BaseEvent { return &e.BaseEvent }
+
+// RawEvent is used for eventTypes that don't have a typed struct yet; the
+// payload is kept as-is so handlers can decode it themselves.
+type RawEvent struct {
+	BaseEvent
+	Payload json.RawMessage `json:"-"`
+}
+
+func (e *RawEvent) base() *BaseEvent { return &e.BaseEvent }
+
+// decodeEvent builds the typed Event for envelope.EventType and decodes
+// envelope.Payload into it.
+func decodeEvent(envelope Envelope) (Event, error) {
+	var event Event
+	switch envelope.EventType {
+	case EventTypeRecorderExited:
+		event = &RecordingExitEvent{}
+	case EventTypeUploaded:
+		event = &UploadedEvent{}
+	case EventTypeSliceStarted:
+		event = &SliceStartEvent{}
+	default:
+		event = &RawEvent{Payload: envelope.Payload}
+	}
+
+	if raw, ok := event.(*RawEvent); ok {
+		raw.BaseEvent = BaseEvent{NoticeID: envelope.NoticeID, Type: envelope.EventType, NotifyMs: envelope.NotifyMs}
+		return raw, nil
+	}
+
+	if len(envelope.Payload) > 0 {
+		if err := json.Unmarshal(envelope.Payload, event); err != nil {
+			return nil, fmt.Errorf("error decoding payload for eventType %d: %v", envelope.EventType, err)
+		}
+	}
+
+	if bs, ok := event.(baseSetter); ok {
+		b := bs.base()
+		b.NoticeID = envelope.NoticeID
+		b.Type = envelope.EventType
+		b.NotifyMs = envelope.NotifyMs
+	}
+
+	return event, nil
+}
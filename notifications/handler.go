@@ -0,0 +1,187 @@
+// Package notifications receives Agora Cloud Recording Notification Callback
+// events (upload complete, recording exited, slice created, etc.) over HTTP
+// and dispatches them to registered handlers.
+package notifications
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+)
+
+// signatureHeader is the header Agora signs Cloud Recording notification
+// callbacks with.
+const signatureHeader = "Agora-Signature-V2"
+
+// defaultQueueSize bounds how many notifications can be queued for
+// processing before ServeHTTP starts rejecting requests, so a slow handler
+// can't exhaust Agora's retry budget by blocking every incoming callback.
+const defaultQueueSize = 64
+
+// Envelope is the top-level shape of every Agora Cloud Recording
+// notification callback.
+type Envelope struct {
+	NoticeID  string          `json:"noticeId"`
+	ProductID int             `json:"productId"`
+	EventType int             `json:"eventType"`
+	NotifyMs  int64           `json:"notifyMs"`
+	Payload   json.RawMessage `json:"payload"`
+}
+
+// HandlerFunc processes a single decoded notification event.
+type HandlerFunc func(ctx context.Context, event Event) error
+
+// Handler is an http.Handler that verifies, de-duplicates and dispatches
+// Agora Cloud Recording notification callbacks.
+type Handler struct {
+	secret string
+
+	mu       sync.RWMutex
+	handlers map[int]HandlerFunc
+
+	seen *noticeCache
+
+	jobs chan job
+	wg   sync.WaitGroup
+}
+
+type job struct {
+	ctx   context.Context
+	event Event
+	fn    HandlerFunc
+}
+
+// NewHandler creates a Handler that verifies callbacks against secret using
+// the Agora-Signature-V2 header, and processes them with workerCount
+// background workers. workerCount <= 0 defaults to 1.
+func NewHandler(secret string, workerCount int) *Handler {
+	if workerCount <= 0 {
+		workerCount = 1
+	}
+
+	h := &Handler{
+		secret:   secret,
+		handlers: make(map[int]HandlerFunc),
+		seen:     newNoticeCache(1024),
+		jobs:     make(chan job, defaultQueueSize),
+	}
+
+	for i := 0; i < workerCount; i++ {
+		h.wg.Add(1)
+		go h.worker()
+	}
+
+	return h
+}
+
+// RegisterHandler registers fn to be invoked for notifications whose
+// eventType matches. Registering again for the same eventType replaces the
+// previous handler.
+func (h *Handler) RegisterHandler(eventType int, fn func(ctx context.Context, event Event) error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handlers[eventType] = fn
+}
+
+// Close stops accepting new work and waits for already-queued notifications
+// to finish processing.
+func (h *Handler) Close() {
+	close(h.jobs)
+	h.wg.Wait()
+}
+
+func (h *Handler) worker() {
+	defer h.wg.Done()
+	for j := range h.jobs {
+		if err := j.fn(j.ctx, j.event); err != nil {
+			// The HTTP response has already been sent by the time a handler
+			// runs, so there's nothing left to do but log; handlers that
+			// need their own retry/alerting should implement it themselves.
+			log.Printf("notifications: handler for eventType %d returned error: %v", j.event.EventType(), err)
+		}
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "error reading request body", http.StatusBadRequest)
+		return
+	}
+
+	if !h.verifySignature(r.Header.Get(signatureHeader), body) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var envelope Envelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		http.Error(w, "error decoding notification envelope", http.StatusBadRequest)
+		return
+	}
+
+	if !h.seen.markIfNew(envelope.NoticeID) {
+		// Agora retries with the same noticeId until it gets a 2xx; we've
+		// already processed (or are processing) this one, so ack without
+		// dispatching again. markIfNew checks and marks under a single lock
+		// acquisition so two concurrent requests for the same noticeId can't
+		// both pass this check.
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	h.mu.RLock()
+	fn, ok := h.handlers[envelope.EventType]
+	h.mu.RUnlock()
+	if !ok {
+		// No handler registered for this eventType; acknowledge so Agora
+		// doesn't keep retrying a notification nobody wants.
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	event, err := decodeEvent(envelope)
+	if err != nil {
+		h.seen.unmark(envelope.NoticeID)
+		http.Error(w, fmt.Sprintf("error decoding event payload: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	select {
+	case h.jobs <- job{ctx: context.Background(), event: event, fn: fn}:
+		// A detached context, not r.Context(): net/http cancels the request's
+		// context the moment ServeHTTP returns, which happens right after this
+		// send, before a worker ever gets to run the job. Using r.Context()
+		// here would mean every handler sees an already-canceled context.
+		w.WriteHeader(http.StatusOK)
+	default:
+		// Queue is full; reject so Agora retries instead of blocking this
+		// (and every other) request on a slow handler. Undo the reservation
+		// so the retry isn't silently swallowed by markIfNew.
+		h.seen.unmark(envelope.NoticeID)
+		http.Error(w, "notification queue is full", http.StatusServiceUnavailable)
+	}
+}
+
+func (h *Handler) verifySignature(signature string, body []byte) bool {
+	if signature == "" {
+		return false
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(sha256.New, []byte(h.secret))
+	mac.Write(body)
+	return hmac.Equal(expected, mac.Sum(nil))
+}
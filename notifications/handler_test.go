@@ -0,0 +1,138 @@
+package notifications
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySignature(t *testing.T) {
+	h := &Handler{secret: "topsecret"}
+	body := []byte(`{"noticeId":"n1"}`)
+
+	if !h.verifySignature(sign("topsecret", body), body) {
+		t.Fatal("expected a correctly signed body to verify")
+	}
+	if h.verifySignature(sign("wrongsecret", body), body) {
+		t.Fatal("expected a signature from the wrong secret to fail verification")
+	}
+	if h.verifySignature("", body) {
+		t.Fatal("expected an empty signature to fail verification")
+	}
+	if h.verifySignature("not-hex!!", body) {
+		t.Fatal("expected a non-hex signature to fail verification")
+	}
+	if h.verifySignature(sign("topsecret", body), []byte(`{"noticeId":"tampered"}`)) {
+		t.Fatal("expected a signature to fail verification against a different body")
+	}
+}
+
+func TestServeHTTPJobContextIsNotCanceledWhenRequestReturns(t *testing.T) {
+	const eventType = EventTypeUploaded
+	secret := "s3cr3t"
+
+	h := NewHandler(secret, 1)
+	defer h.Close()
+
+	ctxErrCh := make(chan error, 1)
+	h.RegisterHandler(eventType, func(ctx context.Context, event Event) error {
+		// Give ServeHTTP a moment to return before we check ctx, so this
+		// test actually exercises the window where r.Context() would have
+		// been canceled.
+		time.Sleep(20 * time.Millisecond)
+		ctxErrCh <- ctx.Err()
+		return nil
+	})
+
+	body := []byte(`{"noticeId":"n-ctx-1","eventType":30,"payload":{"cname":"c","sid":"s"}}`)
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(body)))
+	req.Header.Set(signatureHeader, sign(secret, body))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", rr.Code, rr.Body.String())
+	}
+
+	select {
+	case err := <-ctxErrCh:
+		if err != nil {
+			t.Fatalf("handler observed a canceled context: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("handler was never invoked")
+	}
+}
+
+func TestServeHTTPRejectsInvalidSignature(t *testing.T) {
+	h := NewHandler("s3cr3t", 1)
+	defer h.Close()
+
+	body := []byte(`{"noticeId":"n-bad-sig","eventType":30,"payload":{}}`)
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(body)))
+	req.Header.Set(signatureHeader, sign("wrong-secret", body))
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status 401, got %d", rr.Code)
+	}
+}
+
+func TestServeHTTPDeduplicatesSameNoticeID(t *testing.T) {
+	secret := "s3cr3t"
+	h := NewHandler(secret, 1)
+	defer h.Close()
+
+	var calls int
+	done := make(chan struct{}, 2)
+	h.RegisterHandler(EventTypeUploaded, func(ctx context.Context, event Event) error {
+		calls++
+		done <- struct{}{}
+		return nil
+	})
+
+	body := []byte(`{"noticeId":"n-dup","eventType":30,"payload":{}}`)
+	sig := sign(secret, body)
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(string(body)))
+		req.Header.Set(signatureHeader, sig)
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("request %d: expected status 200, got %d", i, rr.Code)
+		}
+	}
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("handler was never invoked")
+	}
+
+	// Give a potential second dispatch a chance to land before asserting.
+	select {
+	case <-done:
+		t.Fatal("handler was invoked twice for the same noticeId")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected exactly one dispatch, got %d", calls)
+	}
+}